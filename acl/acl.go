@@ -0,0 +1,279 @@
+// Package acl provides per-document access control for converted chunks.
+//
+// Each document is encrypted under its own session key, derived from a
+// deployment-wide root secret and a per-document salt. The session key is
+// never stored in the clear: it is wrapped once per authorized principal via
+// ECDH between an ephemeral ingest key and the principal's public key, and
+// the wrapped copies are kept in a small manifest next to the document.
+// Revoking access only means deleting an entry from that manifest -- the
+// encrypted chunks themselves are never touched.
+package acl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WrappedKey is a document's session key, wrapped for a single principal.
+type WrappedKey struct {
+	Principal       string `json:"principal"`
+	EphemeralPubKey []byte `json:"ephemeral_pub_key"`
+	Ciphertext      []byte `json:"ciphertext"`
+}
+
+// Manifest lists who can decrypt a document's chunks. It deliberately never
+// contains the document's plaintext session key.
+type Manifest struct {
+	DocID  string       `json:"doc_id"`
+	Salt   []byte       `json:"salt"`
+	Grants []WrappedKey `json:"grants"`
+}
+
+// Store reads and writes manifests, one small JSON file per document.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns a Store that keeps manifests under dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) manifestPath(docID string) string {
+	return filepath.Join(s.dir, docID+".acl.json")
+}
+
+func (s *Store) load(docID string) (*Manifest, error) {
+	raw, err := os.ReadFile(s.manifestPath(docID))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("unable to parse ACL manifest for %s: %w", docID, err)
+	}
+	return &m, nil
+}
+
+func (s *Store) save(m *Manifest) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.manifestPath(m.DocID), raw, 0600)
+}
+
+// DeriveSessionKey derives a document's 32-byte AES-256 session key from the
+// deployment's root secret and a per-document salt, following the HKDF
+// extract-then-expand construction (RFC 5869) over HMAC-SHA256.
+func DeriveSessionKey(rootSecret, docSalt []byte) []byte {
+	prk := hkdfExtract(rootSecret, docSalt)
+	return hkdfExpand(prk, []byte("rag-extension-poc chunk session key"), 32)
+}
+
+func hkdfExtract(secret, salt []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(secret)
+	return mac.Sum(nil)
+}
+
+func hkdfExpand(prk, info []byte, length int) []byte {
+	var out, block []byte
+	for counter := byte(1); len(out) < length; counter++ {
+		mac := hmac.New(sha256.New, prk)
+		mac.Write(block)
+		mac.Write(info)
+		mac.Write([]byte{counter})
+		block = mac.Sum(nil)
+		out = append(out, block...)
+	}
+	return out[:length]
+}
+
+// EncryptChunk seals plaintext under key using AES-256-GCM, prefixing the
+// returned ciphertext with its random nonce.
+func EncryptChunk(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext shorter than nonce")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Grant authorizes principal to read docID by wrapping sessionKey under a
+// key exchanged between a fresh ephemeral key and principalPubKey. Granting
+// the same principal again replaces their existing wrapped key.
+func (s *Store) Grant(docID string, salt, sessionKey []byte, principal string, principalPubKey *ecdh.PublicKey) error {
+	curve := ecdh.P256()
+	ephemeral, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("unable to generate ephemeral key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(principalPubKey)
+	if err != nil {
+		return fmt.Errorf("ECDH exchange with %s failed: %w", principal, err)
+	}
+	wrapKey := sha256.Sum256(shared)
+	wrapped, err := EncryptChunk(wrapKey[:], sessionKey)
+	if err != nil {
+		return fmt.Errorf("unable to wrap session key for %s: %w", principal, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.load(docID)
+	if err != nil {
+		m = &Manifest{DocID: docID, Salt: salt}
+	}
+	m.Grants = append(removeGrant(m.Grants, principal), WrappedKey{
+		Principal:       principal,
+		EphemeralPubKey: ephemeral.PublicKey().Bytes(),
+		Ciphertext:      wrapped,
+	})
+	return s.save(m)
+}
+
+// Revoke removes principal's access to docID. Only the manifest is
+// rewritten; the document's encrypted chunks are untouched.
+func (s *Store) Revoke(docID, principal string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.load(docID)
+	if err != nil {
+		return fmt.Errorf("no ACL manifest for %s: %w", docID, err)
+	}
+	m.Grants = removeGrant(m.Grants, principal)
+	return s.save(m)
+}
+
+func removeGrant(grants []WrappedKey, principal string) []WrappedKey {
+	out := make([]WrappedKey, 0, len(grants))
+	for _, g := range grants {
+		if g.Principal != principal {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// Authorized reports whether principal may read docID. A missing manifest or
+// a principal absent from it are indistinguishable, both reporting false.
+func (s *Store) Authorized(principal, docID string) bool {
+	m, err := s.load(docID)
+	if err != nil {
+		return false
+	}
+	for _, g := range m.Grants {
+		if g.Principal == principal {
+			return true
+		}
+	}
+	return false
+}
+
+// DecryptChunk unwraps docID's session key for principal using their ECDH
+// private key, then decrypts ciphertext with it. Unauthorized callers get an
+// error and nothing else -- not even confirmation that docID exists.
+func (s *Store) DecryptChunk(principal, docID string, principalPrivKey *ecdh.PrivateKey, ciphertext []byte) ([]byte, error) {
+	m, err := s.load(docID)
+	if err != nil {
+		return nil, fmt.Errorf("not authorized")
+	}
+	for _, g := range m.Grants {
+		if g.Principal != principal {
+			continue
+		}
+		ephPub, err := ecdh.P256().NewPublicKey(g.EphemeralPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("stored ephemeral key for %s is invalid: %w", principal, err)
+		}
+		shared, err := principalPrivKey.ECDH(ephPub)
+		if err != nil {
+			return nil, fmt.Errorf("ECDH exchange with %s failed: %w", principal, err)
+		}
+		wrapKey := sha256.Sum256(shared)
+		sessionKey, err := decrypt(wrapKey[:], g.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap session key for %s: %w", principal, err)
+		}
+		return decrypt(sessionKey, ciphertext)
+	}
+	return nil, fmt.Errorf("not authorized")
+}
+
+// FetchPrincipalPublicKey fetches the ECDH public key GitHub user login has
+// registered with trustRootURL (a service exposing PEM-encoded P-256 keys
+// keyed by login, e.g. "https://keys.example.com/%s"). GitHub's own
+// users/{login}/keys endpoint serves SSH keys, which are not usable for
+// ECDH directly, so installations that want to key off real GitHub SSH keys
+// need a small translation service in front of trustRootURL.
+func FetchPrincipalPublicKey(trustRootURL, login string) (*ecdh.PublicKey, error) {
+	resp, err := http.Get(fmt.Sprintf(trustRootURL, login))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch public key for %s: %w", login, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch public key for %s: %s", login, resp.Status)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read public key for %s: %w", login, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in public key for %s", login)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key for %s: %w", login, err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key for %s is not ECDSA", login)
+	}
+	return ecdsaKey.ECDH()
+}
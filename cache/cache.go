@@ -0,0 +1,197 @@
+// Package cache is a content-addressed store for document conversion
+// output, modeled on BuildKit's build-cache metadata: entries are keyed by
+// the hash of their input plus the converter that produced them, and carry
+// enough usage accounting to support LRU eviction.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry describes one cached conversion. Size, CreatedAt, LastUsedAt, and
+// UsageCount exist to support eviction and operator visibility; LastUsedAt
+// and UsageCount are updated on every Get hit.
+type Entry struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Mutable     bool      `json:"mutable"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	UsageCount  int       `json:"usage_count"`
+}
+
+// Store persists conversion output under dir: one blob per entry plus a
+// single index.json tracking the Entry metadata.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns a Store backed by dir, creating it if necessary.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs"), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Key computes the content-addressed cache key for a conversion: the hash
+// of the input bytes, the converter's version, and its options.
+func Key(input []byte, converterVersion, converterOpts string) string {
+	h := sha256.New()
+	h.Write(input)
+	h.Write([]byte(converterVersion))
+	h.Write([]byte(converterOpts))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "index.json")
+}
+
+func (s *Store) blobPath(id string) string {
+	return filepath.Join(s.dir, "blobs", id)
+}
+
+func (s *Store) loadIndex() (map[string]*Entry, error) {
+	index := map[string]*Entry{}
+	raw, err := os.ReadFile(s.indexPath())
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cache index: %w", err)
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("unable to parse cache index: %w", err)
+	}
+	return index, nil
+}
+
+func (s *Store) saveIndex(index map[string]*Entry) error {
+	raw, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.indexPath(), raw, 0644)
+}
+
+// Get returns the cached output for id, bumping its LastUsedAt and
+// UsageCount on a hit.
+func (s *Store) Get(id string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.loadIndex()
+	if err != nil {
+		return nil, false, err
+	}
+	entry, ok := index[id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	blob, err := os.ReadFile(s.blobPath(id))
+	if err != nil {
+		return nil, false, fmt.Errorf("cache entry %s has no blob: %w", id, err)
+	}
+
+	entry.LastUsedAt = time.Now()
+	entry.UsageCount++
+	if err := s.saveIndex(index); err != nil {
+		return nil, false, err
+	}
+	return blob, true, nil
+}
+
+// Put stores output under id, overwriting any existing entry.
+func (s *Store) Put(id, description string, mutable bool, output []byte) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.WriteFile(s.blobPath(id), output, 0644); err != nil {
+		return nil, fmt.Errorf("unable to write cache blob %s: %w", id, err)
+	}
+
+	index, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entry := &Entry{
+		ID:          id,
+		Description: description,
+		Mutable:     mutable,
+		Size:        int64(len(output)),
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		UsageCount:  0,
+	}
+	index[id] = entry
+	if err := s.saveIndex(index); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// List returns every entry's metadata, most recently used first.
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(index))
+	for _, e := range index {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsedAt.After(entries[j].LastUsedAt)
+	})
+	return entries, nil
+}
+
+// Prune evicts entries least-recently-used first until the cache's total
+// size is at or below maxBytes.
+func (s *Store) Prune(maxBytes int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*Entry, 0, len(index))
+	var total int64
+	for _, e := range index {
+		entries = append(entries, e)
+		total += e.Size
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsedAt.Before(entries[j].LastUsedAt)
+	})
+
+	for _, e := range entries {
+		if total <= maxBytes {
+			break
+		}
+		if err := os.Remove(s.blobPath(e.ID)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove cache blob %s: %w", e.ID, err)
+		}
+		delete(index, e.ID)
+		total -= e.Size
+	}
+	return s.saveIndex(index)
+}
@@ -0,0 +1,179 @@
+// Package chunker splits converted Markdown into retrieval-sized chunks on
+// heading boundaries, recording enough position information (heading path,
+// source offsets) for the agent to cite where an answer came from.
+package chunker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Options controls how Split sizes chunks. Token counts are approximated by
+// whitespace-delimited word counts -- good enough to bound chunk size
+// without pulling in a model-specific tokenizer.
+type Options struct {
+	// TargetTokens is the chunk size Split aims for before starting a new
+	// chunk.
+	TargetTokens int
+
+	// OverlapTokens is how much trailing content from one chunk is
+	// repeated at the start of the next when Split divides an oversized
+	// section, so a retrieved chunk keeps context from just before it.
+	// Chunks from different sections do not overlap.
+	OverlapTokens int
+}
+
+// DefaultOptions are what convertDocx chunks with, and what Split falls
+// back to when opts.TargetTokens is not positive.
+var DefaultOptions = Options{TargetTokens: 400, OverlapTokens: 40}
+
+// Chunk is one piece of a converted document.
+type Chunk struct {
+	// Index is the chunk's position within its document, starting at 0.
+	Index int `json:"index"`
+
+	// HeadingPath is the stack of headings (outermost first) the chunk
+	// falls under, e.g. ["Setup", "Installing dependencies"].
+	HeadingPath []string `json:"heading_path"`
+
+	// Content is the chunk's Markdown text.
+	Content string `json:"content"`
+
+	// StartOffset and EndOffset are byte offsets into the source Markdown.
+	StartOffset int `json:"start_offset"`
+	EndOffset   int `json:"end_offset"`
+}
+
+// section is a single heading-delimited block of the source Markdown,
+// before word-count splitting.
+type section struct {
+	headingPath []string
+	text        string
+	startOffset int
+}
+
+// Split divides markdown into Chunks, first on heading boundaries and then,
+// for sections still over opts.TargetTokens, on whitespace with
+// opts.OverlapTokens words of repeated context between consecutive chunks.
+func Split(markdown string, opts Options) []Chunk {
+	if opts.TargetTokens <= 0 {
+		opts = DefaultOptions
+	}
+
+	var chunks []Chunk
+	for _, sec := range sectionize(markdown) {
+		for _, c := range splitSection(sec, opts) {
+			c.Index = len(chunks)
+			chunks = append(chunks, c)
+		}
+	}
+	return chunks
+}
+
+// sectionize walks markdown line by line, starting a new section at every
+// "#"-prefixed heading line and tracking the current heading path by level.
+func sectionize(markdown string) []section {
+	var sections []section
+	var path []string
+	var body strings.Builder
+	sectionStart := 0
+	offset := 0
+
+	flush := func(end int) {
+		raw := body.String()
+		text := strings.TrimSpace(raw)
+		if text != "" {
+			sections = append(sections, section{
+				headingPath: append([]string(nil), path...),
+				text:        text,
+				startOffset: sectionStart + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace)),
+			})
+		}
+		body.Reset()
+	}
+
+	lines := strings.SplitAfter(markdown, "\n")
+	for _, line := range lines {
+		if level, title := headingLine(line); level > 0 {
+			flush(offset)
+			if level > len(path) {
+				path = append(path, title)
+			} else {
+				path = append(path[:level-1], title)
+			}
+			sectionStart = offset + len(line)
+		} else {
+			body.WriteString(line)
+		}
+		offset += len(line)
+	}
+	flush(offset)
+	return sections
+}
+
+// headingLine reports the level and title of a Markdown ATX heading line
+// ("## Title\n"), or 0 if line isn't one.
+func headingLine(line string) (level int, title string) {
+	trimmed := strings.TrimLeft(line, " ")
+	level = 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level >= len(trimmed) || trimmed[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(trimmed[level:])
+}
+
+// splitSection further divides a section's text into word-count-bounded
+// chunks when it exceeds opts.TargetTokens.
+func splitSection(sec section, opts Options) []Chunk {
+	words := strings.Fields(sec.text)
+	if len(words) <= opts.TargetTokens {
+		return []Chunk{{
+			HeadingPath: sec.headingPath,
+			Content:     sec.text,
+			StartOffset: sec.startOffset,
+			EndOffset:   sec.startOffset + len(sec.text),
+		}}
+	}
+
+	step := opts.TargetTokens - opts.OverlapTokens
+	if step <= 0 {
+		step = opts.TargetTokens
+	}
+
+	// wordStart and wordEnd hold each word's byte offsets within sec.text,
+	// so a chunk's offsets span its first to last word in the source.
+	var wordStart, wordEnd []int
+	inWord := false
+	for i, r := range sec.text {
+		if space := unicode.IsSpace(r); !space && !inWord {
+			wordStart, inWord = append(wordStart, i), true
+		} else if space && inWord {
+			wordEnd, inWord = append(wordEnd, i), false
+		}
+	}
+	if inWord {
+		wordEnd = append(wordEnd, len(sec.text))
+	}
+
+	var chunks []Chunk
+	for start := 0; start < len(words); start += step {
+		end := start + opts.TargetTokens
+		if end > len(words) {
+			end = len(words)
+		}
+		content := strings.Join(words[start:end], " ")
+		chunks = append(chunks, Chunk{
+			HeadingPath: sec.headingPath,
+			Content:     content,
+			StartOffset: sec.startOffset + wordStart[start],
+			EndOffset:   sec.startOffset + wordEnd[end-1],
+		})
+		if end == len(words) {
+			break
+		}
+	}
+	return chunks
+}
@@ -0,0 +1,55 @@
+// Command rag-credential-env is the built-in credentials.Helper backing
+// "env": it speaks the docker-credential-helpers protocol but always
+// answers from GITHUB_TOKEN, preserving the extension's original behavior
+// for installations that haven't configured a real credential helper.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: rag-credential-env <get|store|erase>")
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "get":
+		if err := get(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "store", "erase":
+		// GITHUB_TOKEN is read-only from this helper's point of view; it is
+		// managed by whatever sets the environment, not by this protocol.
+	default:
+		fmt.Fprintf(os.Stderr, "rag-credential-env: unknown command %q\n", os.Args[1])
+		os.Exit(1)
+	}
+}
+
+func get() error {
+	serverURL, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("unable to read server URL: %w", err)
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("credentials not found: GITHUB_TOKEN is not set")
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(struct {
+		ServerURL string `json:"ServerURL"`
+		Username  string `json:"Username"`
+		Secret    string `json:"Secret"`
+	}{
+		ServerURL: string(serverURL),
+		Username:  "x-access-token",
+		Secret:    token,
+	})
+}
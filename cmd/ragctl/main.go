@@ -0,0 +1,177 @@
+// Command ragctl is a small operator CLI for exercising the converter
+// registry and the ACL-encrypted chunk store offline, without running the
+// full extension.
+package main
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/copilot-extensions/rag-extension/acl"
+	"github.com/copilot-extensions/rag-extension/chunker"
+	"github.com/copilot-extensions/rag-extension/converters"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "convert":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		err = convert(os.Args[2])
+	case "read":
+		if len(os.Args) != 6 {
+			usage()
+		}
+		err = read(os.Args[2], os.Args[3], os.Args[4], os.Args[5])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ragctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ragctl convert <file>")
+	fmt.Fprintln(os.Stderr, "       ragctl read <data-dir> <docID> <principal> <principal-key.pem>")
+	os.Exit(1)
+}
+
+func convert(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	converter, ok := converters.For(filepath.Ext(path))
+	if !ok {
+		return fmt.Errorf("no converter registered for %s files", filepath.Ext(path))
+	}
+
+	markdown, err := converter.Convert(path, data)
+	if err != nil {
+		return fmt.Errorf("unable to convert %s: %w", path, err)
+	}
+
+	fmt.Println(markdown)
+	fmt.Fprintln(os.Stderr, "---")
+
+	chunks := chunker.Split(markdown, chunker.DefaultOptions)
+	for _, c := range chunks {
+		fmt.Fprintf(os.Stderr, "chunk %d %v [%d:%d] (%d bytes)\n",
+			c.Index, c.HeadingPath, c.StartOffset, c.EndOffset, len(c.Content))
+	}
+
+	return nil
+}
+
+// read decrypts and prints docID's chunks from dir as principal, exercising
+// the same acl.Store.Authorized/DecryptChunk path a server-side retrieval
+// step (agent.Service, once that package exists in this checkout -- see
+// ingest.Index's doc comment) would run per incoming chat request after
+// resolving the caller's identity via oauth.Service.
+func read(dir, docID, principal, keyPath string) error {
+	privKey, err := readPrincipalKey(keyPath)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", keyPath, err)
+	}
+
+	store := acl.NewStore(dir)
+	if !store.Authorized(principal, docID) {
+		return fmt.Errorf("%s is not authorized to read %s", principal, docID)
+	}
+
+	chunkPaths, err := chunkFiles(dir, docID)
+	if err != nil {
+		return err
+	}
+	if len(chunkPaths) == 0 {
+		return fmt.Errorf("no chunks found for %s in %s", docID, dir)
+	}
+
+	for _, path := range chunkPaths {
+		ciphertext, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", path, err)
+		}
+		plaintext, err := store.DecryptChunk(principal, docID, privKey, ciphertext)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt %s: %w", path, err)
+		}
+		fmt.Println(string(plaintext))
+		fmt.Println("---")
+	}
+	return nil
+}
+
+// readPrincipalKey parses a PEM-encoded PKCS8 ECDSA P-256 private key --
+// FetchPrincipalPublicKey's counterpart on the decrypting side -- as an
+// ecdh.PrivateKey.
+func readPrincipalKey(path string) (*ecdh.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not ECDSA")
+	}
+	return ecdsaKey.ECDH()
+}
+
+var chunkFileRe = regexp.MustCompile(`-(\d+)\.md$`)
+
+// chunkFiles returns docID's .md chunk files under dir, in chunk-index
+// order.
+func chunkFiles(dir, docID string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, docID+"-*.md"))
+	if err != nil {
+		return nil, err
+	}
+	// The glob also matches other documents whose docID starts with
+	// docID + "-"; keep only "{docID}-{index}.md".
+	var own []string
+	for _, m := range matches {
+		rest := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), docID+"-"), ".md")
+		if _, err := strconv.Atoi(rest); err == nil {
+			own = append(own, m)
+		}
+	}
+	sort.Slice(own, func(i, j int) bool {
+		return chunkIndex(own[i]) < chunkIndex(own[j])
+	})
+	return own, nil
+}
+
+func chunkIndex(path string) int {
+	m := chunkFileRe.FindStringSubmatch(path)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
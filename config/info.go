@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 type Info struct {
@@ -18,13 +19,26 @@ type Info struct {
 
 	// ClientSecret comes from your configured GitHub app
 	ClientSecret string
+
+	// RootSecret seeds the per-document session keys the acl package
+	// derives for encrypting retrieved chunks. Treat it like any other
+	// credential: rotating it invalidates every document's session key.
+	RootSecret string
+
+	// CredentialHelpers maps a source host to the name of the
+	// credentials.Helper that should authenticate requests to it, e.g.
+	// {"github.example.com": "osxkeychain"}. Hosts without an entry fall
+	// back to the built-in "env" helper (GITHUB_TOKEN).
+	CredentialHelpers map[string]string
 }
 
 const (
-	portEnv         = "PORT"
-	clientIdEnv     = "CLIENT_ID"
-	clientSecretEnv = "CLIENT_SECRET"
-	fqdnEnv         = "FQDN"
+	portEnv              = "PORT"
+	clientIdEnv          = "CLIENT_ID"
+	clientSecretEnv      = "CLIENT_SECRET"
+	fqdnEnv              = "FQDN"
+	rootSecretEnv        = "ROOT_SECRET"
+	credentialHelpersEnv = "CREDENTIAL_HELPERS"
 )
 
 func New() (*Info, error) {
@@ -52,10 +66,36 @@ func New() (*Info, error) {
 	}
 	fmt.Println("CLIENT_SECRET:", clientSecret)
 
+	rootSecret := os.Getenv(rootSecretEnv)
+	if rootSecret == "" {
+		return nil, fmt.Errorf("%s environment variable required", rootSecretEnv)
+	}
+
 	return &Info{
-		Port:         port,
-		FQDN:         fqdn,
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
+		Port:              port,
+		FQDN:              fqdn,
+		ClientID:          clientID,
+		ClientSecret:      clientSecret,
+		RootSecret:        rootSecret,
+		CredentialHelpers: ParseCredentialHelpers(os.Getenv(credentialHelpersEnv)),
 	}, nil
 }
+
+// ParseCredentialHelpers parses a comma-separated "host=helper" list, e.g.
+// "github.example.com=osxkeychain,wiki.internal=secretservice". It is
+// exported so callers that run before config.New() (main's boot-time
+// ingestion) can build the same mapping.
+func ParseCredentialHelpers(raw string) map[string]string {
+	if raw == "" {
+		return nil
+	}
+	helpers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		host, helper, ok := strings.Cut(pair, "=")
+		if !ok || host == "" || helper == "" {
+			continue
+		}
+		helpers[host] = helper
+	}
+	return helpers
+}
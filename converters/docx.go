@@ -0,0 +1,529 @@
+package converters
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DocxConverter renders word/document.xml to Markdown, preserving the
+// structure that matters for RAG chunking: paragraphs, heading levels
+// (w:pStyle values Heading1..Heading9), GFM tables, list items, and
+// hyperlinks resolved against word/_rels/document.xml.rels.
+//
+// It does not yet read numbering.xml, so list items always render as
+// unordered ("- ") even when Word would have numbered them -- telling
+// numbered from bulleted lists apart requires following a paragraph's
+// w:numId into numbering.xml's abstract numbering definitions, which is
+// follow-up work.
+type DocxConverter struct{}
+
+func (c *DocxConverter) Version() string { return "docx-xml-markdown-v1" }
+
+func (c *DocxConverter) Convert(path string, data []byte) (string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("impossibile aprire il file .docx: %w", err)
+	}
+
+	rels, err := readRelationships(reader)
+	if err != nil {
+		return "", err
+	}
+
+	docXML, err := readZipFile(reader, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	ctx := &docxCtx{rels: rels}
+	return ctx.convertDocument(docXML)
+}
+
+func readZipFile(reader *zip.Reader, name string) ([]byte, error) {
+	for _, f := range reader.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("impossibile aprire %s: %w", name, err)
+		}
+		defer rc.Close()
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, rc); err != nil {
+			return nil, fmt.Errorf("impossibile leggere %s: %w", name, err)
+		}
+		return buf.Bytes(), nil
+	}
+	if name == "word/document.xml" {
+		return nil, fmt.Errorf("document.xml non trovato nel file .docx")
+	}
+	return nil, nil
+}
+
+// readRelationships parses word/_rels/document.xml.rels into a map from
+// relationship ID to target (the URL a hyperlink points at).
+func readRelationships(reader *zip.Reader) (map[string]string, error) {
+	raw, err := readZipFile(reader, "word/_rels/document.xml.rels")
+	if err != nil || raw == nil {
+		return map[string]string{}, nil
+	}
+
+	var rels struct {
+		Relationship []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(raw, &rels); err != nil {
+		return nil, fmt.Errorf("impossibile analizzare document.xml.rels: %w", err)
+	}
+
+	out := make(map[string]string, len(rels.Relationship))
+	for _, r := range rels.Relationship {
+		out[r.ID] = r.Target
+	}
+	return out, nil
+}
+
+// docxCtx carries state shared across the document's conversion, currently
+// just the relationship map hyperlinks are resolved against.
+type docxCtx struct {
+	rels map[string]string
+}
+
+func (ctx *docxCtx) convertDocument(docXML []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(docXML))
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("elemento <w:body> non trovato in document.xml")
+		}
+		if err != nil {
+			return "", fmt.Errorf("impossibile analizzare document.xml: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "body" {
+			return ctx.decodeBody(d, se)
+		}
+	}
+}
+
+func (ctx *docxCtx) decodeBody(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var blocks []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "p":
+				p, err := ctx.decodeParagraph(d, se)
+				if err != nil {
+					return "", err
+				}
+				if block := p.markdown(); block != "" {
+					blocks = append(blocks, block)
+				}
+			case "tbl":
+				table, err := ctx.decodeTable(d, se)
+				if err != nil {
+					return "", err
+				}
+				blocks = append(blocks, table)
+			default:
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return strings.Join(blocks, "\n\n"), nil
+			}
+		}
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// paragraph is the decoded content of a single w:p element.
+type paragraph struct {
+	headingLevel int
+	listItem     bool
+	inline       string
+}
+
+func (p *paragraph) markdown() string {
+	text := strings.TrimSpace(p.inline)
+	if text == "" {
+		return ""
+	}
+	switch {
+	case p.headingLevel > 0:
+		return strings.Repeat("#", p.headingLevel) + " " + text
+	case p.listItem:
+		return "- " + text
+	default:
+		return text
+	}
+}
+
+func (ctx *docxCtx) decodeParagraph(d *xml.Decoder, start xml.StartElement) (*paragraph, error) {
+	p := &paragraph{}
+	var inline strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "pPr":
+				style, numbered, err := ctx.decodePPr(d, se)
+				if err != nil {
+					return nil, err
+				}
+				p.headingLevel = headingLevel(style)
+				p.listItem = numbered
+			case "r":
+				text, err := ctx.decodeRun(d, se)
+				if err != nil {
+					return nil, err
+				}
+				inline.WriteString(text)
+			case "hyperlink":
+				link, err := ctx.decodeHyperlink(d, se)
+				if err != nil {
+					return nil, err
+				}
+				inline.WriteString(link)
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				p.inline = inline.String()
+				return p, nil
+			}
+		}
+	}
+	p.inline = inline.String()
+	return p, nil
+}
+
+// headingLevel maps a w:pStyle value like "Heading2" to 2, or 0 if style
+// isn't a heading style.
+func headingLevel(style string) int {
+	const prefix = "Heading"
+	if !strings.HasPrefix(style, prefix) {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(style, prefix))
+	if err != nil || n < 1 {
+		return 0
+	}
+	if n > 6 {
+		n = 6
+	}
+	return n
+}
+
+// decodePPr reads a paragraph's properties, returning its style ID (if any)
+// and whether it carries numbering (i.e. is a list item).
+func (ctx *docxCtx) decodePPr(d *xml.Decoder, start xml.StartElement) (style string, numbered bool, err error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", false, err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "pStyle":
+				style = attrVal(se, "val")
+				if err := d.Skip(); err != nil {
+					return "", false, err
+				}
+			case "numPr":
+				numbered = true
+				if err := d.Skip(); err != nil {
+					return "", false, err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return "", false, err
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return style, numbered, nil
+			}
+		}
+	}
+	return style, numbered, nil
+}
+
+// decodeRun reads a w:r run's text, translating w:tab and w:br into
+// whitespace.
+func (ctx *docxCtx) decodeRun(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "t":
+				chars, err := readCharData(d, se)
+				if err != nil {
+					return "", err
+				}
+				text.WriteString(chars)
+			case "tab":
+				text.WriteString("\t")
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			case "br":
+				text.WriteString("\n")
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return text.String(), nil
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+// decodeHyperlink reads a w:hyperlink's runs and resolves its r:id against
+// ctx.rels, rendering "[text](target)". If the relationship is missing, the
+// text is rendered unlinked rather than dropped.
+func (ctx *docxCtx) decodeHyperlink(d *xml.Decoder, start xml.StartElement) (string, error) {
+	relID := attrVal(start, "id")
+	var text strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if se.Name.Local == "r" {
+				run, err := ctx.decodeRun(d, se)
+				if err != nil {
+					return "", err
+				}
+				text.WriteString(run)
+			} else if err := d.Skip(); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return ctx.renderLink(relID, text.String()), nil
+			}
+		}
+	}
+	return ctx.renderLink(relID, text.String()), nil
+}
+
+func (ctx *docxCtx) renderLink(relID, text string) string {
+	target, ok := ctx.rels[relID]
+	if !ok || target == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, target)
+}
+
+// decodeTable renders a w:tbl as a GFM table, treating its first row as the
+// header.
+func (ctx *docxCtx) decodeTable(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var rows [][]string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if se.Name.Local == "tr" {
+				row, err := ctx.decodeRow(d, se)
+				if err != nil {
+					return "", err
+				}
+				rows = append(rows, row)
+			} else if err := d.Skip(); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return renderGFMTable(rows), nil
+			}
+		}
+	}
+	return renderGFMTable(rows), nil
+}
+
+func (ctx *docxCtx) decodeRow(d *xml.Decoder, start xml.StartElement) ([]string, error) {
+	var cells []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if se.Name.Local == "tc" {
+				cell, err := ctx.decodeCell(d, se)
+				if err != nil {
+					return nil, err
+				}
+				cells = append(cells, cell)
+			} else if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return cells, nil
+			}
+		}
+	}
+	return cells, nil
+}
+
+func (ctx *docxCtx) decodeCell(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var paragraphs []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if se.Name.Local == "p" {
+				p, err := ctx.decodeParagraph(d, se)
+				if err != nil {
+					return "", err
+				}
+				if text := strings.TrimSpace(p.inline); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			} else if err := d.Skip(); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return strings.Join(paragraphs, "<br>"), nil
+			}
+		}
+	}
+	return strings.Join(paragraphs, "<br>"), nil
+}
+
+func renderGFMTable(rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	var md strings.Builder
+	for i, row := range rows {
+		md.WriteString(renderGFMRow(row, width))
+		md.WriteString("\n")
+		if i == 0 {
+			sep := make([]string, width)
+			for j := range sep {
+				sep[j] = "---"
+			}
+			md.WriteString(renderGFMRow(sep, width))
+			md.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(md.String(), "\n")
+}
+
+func renderGFMRow(cells []string, width int) string {
+	padded := make([]string, width)
+	copy(padded, cells)
+	return "| " + strings.Join(padded, " | ") + " |"
+}
+
+func readCharData(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch se := tok.(type) {
+		case xml.CharData:
+			text.Write(se)
+		case xml.EndElement:
+			if se.Name.Local == start.Name.Local {
+				return text.String(), nil
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+func attrVal(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+package converters
+
+import "strings"
+
+// HTMLConverter renders HTML to Markdown, preserving the structure that
+// matters for RAG chunking: headings (h1-h6), paragraphs, lists, hyperlinks,
+// and tables (as GFM, reusing renderGFMTable from docx.go). It does not
+// build a DOM -- just enough of a streaming tag/text scanner to track block
+// boundaries and the handful of inline elements (a, b/strong, i/em, br) RAG
+// content actually uses. Unknown tags are treated as plain inline text
+// boundaries and otherwise ignored, the same tolerance real browsers give
+// malformed HTML.
+//
+// It does not distinguish ordered from unordered lists (everything renders
+// "- ", matching DocxConverter's equivalent gap) and does not preserve
+// nested tables.
+type HTMLConverter struct{}
+
+func (c *HTMLConverter) Version() string { return "html-markdown-v1" }
+
+func (c *HTMLConverter) Convert(path string, data []byte) (string, error) {
+	s := newHTMLState()
+	s.run(string(data))
+	return strings.Join(s.blocks, "\n\n"), nil
+}
+
+// htmlState walks an HTML document left to right, maintaining just enough
+// state to turn block elements into Markdown blocks and a handful of inline
+// elements into Markdown inline syntax.
+type htmlState struct {
+	blocks []string
+
+	block        strings.Builder // accumulates the current block's inline content
+	headingLevel int
+	listItem     bool
+
+	inTable    bool
+	tableRows  [][]string
+	currentRow []string
+	cell       strings.Builder
+	inCell     bool
+
+	anchors []*htmlAnchor // stack, for nested <a> (rare, but never well-formed HTML's fault)
+	skip    string        // tag name whose content (script/style) is being discarded
+}
+
+type htmlAnchor struct {
+	href string
+	text strings.Builder
+}
+
+func newHTMLState() *htmlState {
+	return &htmlState{}
+}
+
+// dest returns the builder inline text should currently be written to.
+func (s *htmlState) dest() *strings.Builder {
+	if n := len(s.anchors); n > 0 {
+		return &s.anchors[n-1].text
+	}
+	if s.inCell {
+		return &s.cell
+	}
+	return &s.block
+}
+
+func (s *htmlState) run(doc string) {
+	i := 0
+	for i < len(doc) {
+		if doc[i] != '<' {
+			j := strings.IndexByte(doc[i:], '<')
+			var text string
+			if j < 0 {
+				text, i = doc[i:], len(doc)
+			} else {
+				text, i = doc[i:i+j], i+j
+			}
+			if s.skip == "" {
+				s.dest().WriteString(unescapeHTML(text))
+			}
+			continue
+		}
+
+		end := strings.IndexByte(doc[i:], '>')
+		if end < 0 {
+			break
+		}
+		raw := doc[i+1 : i+end]
+		i += end + 1
+
+		if raw == "" || strings.HasPrefix(raw, "!") || strings.HasPrefix(raw, "?") {
+			continue // comment or doctype
+		}
+
+		closing := strings.HasPrefix(raw, "/")
+		if closing {
+			raw = raw[1:]
+		}
+		selfClosing := strings.HasSuffix(raw, "/")
+		if selfClosing {
+			raw = strings.TrimSuffix(raw, "/")
+		}
+		name, attrs := parseTag(raw)
+
+		if s.skip != "" {
+			if closing && name == s.skip {
+				s.skip = ""
+			}
+			continue
+		}
+
+		if closing {
+			s.onClose(name)
+			continue
+		}
+		s.onOpen(name, attrs)
+		if selfClosing || name == "br" || name == "hr" || name == "img" {
+			s.onClose(name)
+		}
+	}
+	s.flush()
+}
+
+func (s *htmlState) onOpen(name string, attrs map[string]string) {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		s.flush()
+		s.headingLevel = int(name[1] - '0')
+	case "p", "div":
+		s.flush()
+	case "li":
+		s.flush()
+		s.listItem = true
+	case "br":
+		s.dest().WriteString("\n")
+	case "a":
+		s.anchors = append(s.anchors, &htmlAnchor{href: attrs["href"]})
+	case "strong", "b":
+		s.dest().WriteString("**")
+	case "em", "i":
+		s.dest().WriteString("*")
+	case "table":
+		s.flush()
+		s.inTable = true
+		s.tableRows = nil
+	case "tr":
+		s.currentRow = nil
+	case "td", "th":
+		s.cell.Reset()
+		s.inCell = true
+	case "script", "style":
+		s.skip = name
+	}
+}
+
+func (s *htmlState) onClose(name string) {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "p", "div", "li":
+		s.flush()
+	case "a":
+		if n := len(s.anchors); n > 0 {
+			a := s.anchors[n-1]
+			s.anchors = s.anchors[:n-1]
+			link := strings.TrimSpace(a.text.String())
+			if a.href != "" {
+				link = "[" + link + "](" + a.href + ")"
+			}
+			s.dest().WriteString(link)
+		}
+	case "strong", "b":
+		s.dest().WriteString("**")
+	case "em", "i":
+		s.dest().WriteString("*")
+	case "td", "th":
+		s.inCell = false
+		s.currentRow = append(s.currentRow, strings.TrimSpace(s.cell.String()))
+	case "tr":
+		s.tableRows = append(s.tableRows, s.currentRow)
+	case "table":
+		if table := renderGFMTable(s.tableRows); table != "" {
+			s.blocks = append(s.blocks, table)
+		}
+		s.inTable = false
+	}
+}
+
+// flush closes out the current block (paragraph, heading, or list item),
+// appending its rendered Markdown to blocks if it has any content.
+func (s *htmlState) flush() {
+	text := strings.TrimSpace(s.block.String())
+	if text != "" {
+		switch {
+		case s.headingLevel > 0:
+			s.blocks = append(s.blocks, strings.Repeat("#", s.headingLevel)+" "+text)
+		case s.listItem:
+			s.blocks = append(s.blocks, "- "+text)
+		default:
+			s.blocks = append(s.blocks, text)
+		}
+	}
+	s.block.Reset()
+	s.headingLevel = 0
+	s.listItem = false
+}
+
+// parseTag splits a tag's raw interior ("a href=\"x\" target=_blank") into
+// its lowercase name and an attribute map. Only simple key="value" or
+// key='value' attributes are recognized; that covers every attribute this
+// converter reads (href).
+func parseTag(raw string) (name string, attrs map[string]string) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	name = strings.ToLower(fields[0])
+	attrs = map[string]string{}
+	for _, field := range fields[1:] {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		val = strings.Trim(val, `"'`)
+		attrs[strings.ToLower(key)] = val
+	}
+	return name, attrs
+}
+
+var htmlUnescaper = strings.NewReplacer(
+	"&amp;", "&",
+	"&lt;", "<",
+	"&gt;", ">",
+	"&quot;", `"`,
+	"&#39;", "'",
+	"&apos;", "'",
+	"&nbsp;", " ",
+)
+
+func unescapeHTML(s string) string {
+	return htmlUnescaper.Replace(s)
+}
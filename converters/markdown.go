@@ -0,0 +1,10 @@
+package converters
+
+// MarkdownConverter passes Markdown source through unchanged.
+type MarkdownConverter struct{}
+
+func (c *MarkdownConverter) Version() string { return "md-passthrough-v1" }
+
+func (c *MarkdownConverter) Convert(path string, data []byte) (string, error) {
+	return string(data), nil
+}
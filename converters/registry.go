@@ -0,0 +1,61 @@
+// Package converters turns source documents into Markdown the chunker and
+// retrieval pipeline can work with, dispatching on file extension through a
+// small registry instead of one hardcoded DOCX-only path.
+package converters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Converter turns the raw bytes of a document into Markdown.
+type Converter interface {
+	// Convert converts data (the file's raw bytes) to Markdown. path is the
+	// source file name, useful for error messages and format quirks that
+	// depend on it.
+	Convert(path string, data []byte) (string, error)
+
+	// Version identifies this converter's output format. It is mixed into
+	// the conversion cache key, so changing how a converter renders
+	// Markdown should bump its Version to avoid serving stale cache hits.
+	Version() string
+}
+
+var registry = map[string]Converter{}
+
+// Register associates a Converter with a file extension (including the
+// leading dot, e.g. ".docx"). Registering the same extension twice replaces
+// the previous converter.
+func Register(ext string, c Converter) {
+	registry[strings.ToLower(ext)] = c
+}
+
+// For returns the Converter registered for ext, if any.
+func For(ext string) (Converter, bool) {
+	c, ok := registry[strings.ToLower(ext)]
+	return c, ok
+}
+
+// Convert looks up the converter for path's extension and runs it.
+func Convert(path string, data []byte) (string, error) {
+	ext := extOf(path)
+	c, ok := For(ext)
+	if !ok {
+		return "", fmt.Errorf("no converter registered for %s files", ext)
+	}
+	return c.Convert(path, data)
+}
+
+func extOf(path string) string {
+	i := strings.LastIndexByte(path, '.')
+	if i < 0 {
+		return ""
+	}
+	return strings.ToLower(path[i:])
+}
+
+func init() {
+	Register(".docx", &DocxConverter{})
+	Register(".md", &MarkdownConverter{})
+	Register(".html", &HTMLConverter{})
+}
@@ -0,0 +1,27 @@
+package converters
+
+import "fmt"
+
+// unsupportedConverter registers a format so For() finds it and callers get
+// a precise "not implemented yet" error instead of "no converter registered"
+// -- the latter reads as a typo'd extension, not a known, deliberate gap.
+type unsupportedConverter struct {
+	format string
+}
+
+func (c *unsupportedConverter) Version() string { return "unsupported-" + c.format + "-v1" }
+
+func (c *unsupportedConverter) Convert(path string, data []byte) (string, error) {
+	return "", fmt.Errorf("%s conversion is not implemented yet (tracked alongside the docx/html/md converters)", c.format)
+}
+
+func init() {
+	// PDF has no text layer to walk the way word/document.xml does -- it
+	// needs either an embedded-text extractor or OCR, neither of which fits
+	// this package's stdlib-only approach. PPTX and XLSX are OOXML zips like
+	// DOCX, so they're the more natural next converters to write following
+	// DocxConverter's pattern (ppt/slides/slideN.xml, xl/worksheets/sheetN.xml).
+	Register(".pdf", &unsupportedConverter{format: "pdf"})
+	Register(".pptx", &unsupportedConverter{format: "pptx"})
+	Register(".xlsx", &unsupportedConverter{format: "xlsx"})
+}
@@ -0,0 +1,88 @@
+// Package credentials implements the docker-credential-helpers wire
+// protocol, so outbound requests to private document sources (Confluence,
+// SharePoint, private S3, internal wikis) can be authenticated without
+// putting secrets in environment variables.
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// Credential is the {ServerURL,Username,Secret} schema docker-credential-*
+// helpers speak on stdin/stdout.
+type Credential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// Helper looks up the credential for a server URL.
+type Helper interface {
+	Get(serverURL string) (Credential, error)
+}
+
+// ExecHelper is the default Helper: it execs a binary named
+// "rag-credential-<Name>" and speaks the same JSON-on-stdin/stdout protocol
+// as docker-credential-helpers ("get" subcommand, server URL on stdin,
+// Credential JSON on stdout).
+type ExecHelper struct {
+	Name string
+}
+
+func (h *ExecHelper) Get(serverURL string) (Credential, error) {
+	binary := "rag-credential-" + h.Name
+
+	cmd := exec.Command(binary, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Credential{}, fmt.Errorf("%s get failed: %w: %s", binary, err, stderr.String())
+	}
+
+	var cred Credential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return Credential{}, fmt.Errorf("%s returned invalid credential JSON: %w", binary, err)
+	}
+	return cred, nil
+}
+
+// Store resolves credentials for a server URL by consulting a configured
+// host -> helper name mapping, falling back to the built-in "env" helper
+// (rag-credential-env, which reads GITHUB_TOKEN) for hosts without one
+// configured. Unlike the in-process os.Getenv read this replaces, the env
+// helper is a separate binary that must be on PATH -- callers should log
+// when Get fails rather than silently treating it as "no credential".
+type Store struct {
+	helpers map[string]string
+}
+
+// NewStore returns a Store that consults helpers (host -> helper name).
+// helpers may be nil.
+func NewStore(helpers map[string]string) *Store {
+	return &Store{helpers: helpers}
+}
+
+// Get resolves the credential to use for serverURL.
+func (s *Store) Get(serverURL string) (Credential, error) {
+	name := "env"
+	if configured := s.helpers[hostOf(serverURL)]; configured != "" {
+		name = configured
+	}
+	return (&ExecHelper{Name: name}).Get(serverURL)
+}
+
+func hostOf(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return serverURL
+	}
+	return u.Host
+}
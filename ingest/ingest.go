@@ -0,0 +1,184 @@
+// Package ingest watches document directories for changes and reindexes
+// them asynchronously, so the extension can run as a long-lived service
+// instead of batch-converting once at boot and never picking up new files.
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// State is a file's position in the ingest pipeline.
+type State string
+
+const (
+	StatePending    State = "pending"
+	StateConverting State = "converting"
+	StateIndexed    State = "indexed"
+	StateFailed     State = "failed"
+)
+
+// FileStatus is a single file's last known ingest state, as reported by
+// /admin/ingest/status.
+type FileStatus struct {
+	Path      string    `json:"path"`
+	State     State     `json:"state"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConvertFunc converts and indexes a single file, the way docxIndexer.convert
+// does in main. Returning an error marks the file StateFailed.
+type ConvertFunc func(path string) error
+
+// Watcher watches a set of directories for CREATE/WRITE/RENAME events,
+// debounces bursts, and dispatches each settled file to convert via a fixed
+// pool of workers.
+type Watcher struct {
+	dirs      []string
+	workers   int
+	debounce  time.Duration
+	convert   ConvertFunc
+	jobs      chan string
+	statuses  sync.Map // path -> FileStatus
+	pending   map[string]*time.Timer
+	pendingMu sync.Mutex
+}
+
+// NewWatcher returns a Watcher that indexes dirs with workers concurrent
+// converters, debouncing filesystem bursts by 200ms.
+func NewWatcher(dirs []string, workers int, convert ConvertFunc) *Watcher {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Watcher{
+		dirs:     dirs,
+		workers:  workers,
+		debounce: 200 * time.Millisecond,
+		convert:  convert,
+		jobs:     make(chan string, 64),
+		pending:  map[string]*time.Timer{},
+	}
+}
+
+// Run watches dirs until stop is closed, dispatching settled file events to
+// the worker pool. It blocks, so callers should run it in a goroutine.
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to start filesystem watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	for _, dir := range w.dirs {
+		if err := fsWatcher.Add(dir); err != nil {
+			return fmt.Errorf("unable to watch %s: %w", dir, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.runWorker()
+		}()
+	}
+
+	for {
+		select {
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				close(w.jobs)
+				wg.Wait()
+				return nil
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) != 0 {
+				// A convert's own rename of its source into processedDir
+				// raises a Rename event for the old path on this same
+				// watcher. Stat rules that out (and any other event for a
+				// path that's already gone) without needing to know
+				// processedDir's location.
+				if _, err := os.Stat(event.Name); err != nil {
+					continue
+				}
+				w.debounced(event.Name)
+			}
+		case _, ok := <-fsWatcher.Errors:
+			if !ok {
+				close(w.jobs)
+				wg.Wait()
+				return nil
+			}
+		case <-stop:
+			close(w.jobs)
+			wg.Wait()
+			return nil
+		}
+	}
+}
+
+// debounced schedules path for conversion after w.debounce, restarting the
+// timer if an event for the same path arrives before it fires.
+func (w *Watcher) debounced(path string) {
+	w.setStatus(path, StatePending, "")
+
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+
+	if t, ok := w.pending[path]; ok {
+		t.Stop()
+	}
+	w.pending[path] = time.AfterFunc(w.debounce, func() {
+		w.pendingMu.Lock()
+		delete(w.pending, path)
+		w.pendingMu.Unlock()
+		w.jobs <- path
+	})
+}
+
+func (w *Watcher) runWorker() {
+	for path := range w.jobs {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		w.setStatus(path, StateConverting, "")
+		if err := w.convert(path); err != nil {
+			w.setStatus(path, StateFailed, err.Error())
+			continue
+		}
+		w.setStatus(path, StateIndexed, "")
+	}
+}
+
+// Reindex forces path through the same pipeline as a filesystem event,
+// bypassing the debounce delay.
+func (w *Watcher) Reindex(path string) {
+	w.setStatus(path, StatePending, "")
+	w.jobs <- path
+}
+
+func (w *Watcher) setStatus(path string, state State, errMsg string) {
+	w.statuses.Store(path, FileStatus{
+		Path:      path,
+		State:     state,
+		Error:     errMsg,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// Status returns every tracked file's last known state, sorted by path.
+func (w *Watcher) Status() []FileStatus {
+	var statuses []FileStatus
+	w.statuses.Range(func(_, v any) bool {
+		statuses = append(statuses, v.(FileStatus))
+		return true
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Path < statuses[j].Path })
+	return statuses
+}
@@ -0,0 +1,79 @@
+package ingest
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot is the set of chunk files known to be fully indexed for each
+// docID, as of the moment it was published.
+type Snapshot struct {
+	ChunkPaths map[string][]string // docID -> chunk file paths
+}
+
+// Index publishes Snapshots atomically: readers always see either the
+// previous complete snapshot or the next one, never a partially updated
+// one. This is what lets chat completions in flight during a reindex read a
+// consistent view of the data instead of racing the ingest worker pool.
+//
+// main wires a single Index through both the boot-time batch pass and the
+// ingest watcher, and docxIndexer.convert publishes to it after every
+// conversion. Writers are serialized by mu so concurrent Publish/Unpublish
+// calls from the watcher's worker pool (INGEST_WORKERS) read-modify-write
+// the map one at a time instead of racing and dropping each other's
+// updates; Load stays lock-free since readers only ever see a fully built
+// Snapshot. agent.Service is not present in this checkout, so nothing calls
+// Load() yet; wiring the agent's retrieval path to it instead of
+// re-scanning ./data on every request is the natural next step once that
+// package exists here.
+type Index struct {
+	ptr atomic.Pointer[Snapshot]
+	mu  sync.Mutex
+}
+
+// NewIndex returns an Index holding an empty Snapshot.
+func NewIndex() *Index {
+	idx := &Index{}
+	idx.ptr.Store(&Snapshot{ChunkPaths: map[string][]string{}})
+	return idx
+}
+
+// Load returns the current Snapshot.
+func (idx *Index) Load() *Snapshot {
+	return idx.ptr.Load()
+}
+
+// Publish atomically replaces docID's chunk paths and republishes the
+// Snapshot, copying the rest of the previous snapshot's entries unchanged.
+func (idx *Index) Publish(docID string, chunkPaths []string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	prev := idx.Load()
+	next := &Snapshot{ChunkPaths: make(map[string][]string, len(prev.ChunkPaths)+1)}
+	for id, paths := range prev.ChunkPaths {
+		next.ChunkPaths[id] = paths
+	}
+	next.ChunkPaths[docID] = chunkPaths
+	idx.ptr.Store(next)
+}
+
+// Unpublish atomically removes docID from the Snapshot, e.g. when a source
+// document disappears upstream and its chunks are archived rather than
+// served.
+func (idx *Index) Unpublish(docID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	prev := idx.Load()
+	if _, ok := prev.ChunkPaths[docID]; !ok {
+		return
+	}
+	next := &Snapshot{ChunkPaths: make(map[string][]string, len(prev.ChunkPaths))}
+	for id, paths := range prev.ChunkPaths {
+		if id != docID {
+			next.ChunkPaths[id] = paths
+		}
+	}
+	idx.ptr.Store(next)
+}
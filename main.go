@@ -2,39 +2,126 @@ package main
 
 import (
 	"crypto/ecdsa"
+	"crypto/subtle"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
-	"io"
+	"sort"
+	"strconv"
 	"strings"
 	"log"
 	"path/filepath"
-	"archive/zip"
-	"bytes"
+	"crypto/rand"
 
+	"context"
+
+	"github.com/copilot-extensions/rag-extension/acl"
 	"github.com/copilot-extensions/rag-extension/agent"
+	"github.com/copilot-extensions/rag-extension/cache"
+	"github.com/copilot-extensions/rag-extension/chunker"
 	"github.com/copilot-extensions/rag-extension/config"
+	"github.com/copilot-extensions/rag-extension/converters"
+	"github.com/copilot-extensions/rag-extension/credentials"
+	"github.com/copilot-extensions/rag-extension/ingest"
 	"github.com/copilot-extensions/rag-extension/oauth"
+	"github.com/copilot-extensions/rag-extension/sources/git"
 
 )
 
 func main() {
-	
-	if err := convertDocx(); err != nil {
+
+	if len(os.Args) > 1 && os.Args[1] == "--prune" {
+		if err := pruneCache(); err != nil {
+			log.Fatalf("Errore durante il prune della cache: %v", err)
+		}
+		return
+	}
+
+	// index is the one atomic snapshot pointer shared by the boot-time batch
+	// pass, the ingest watcher, and (once agent.Service exists in this
+	// checkout) retrieval -- all publishing to and reading from the same
+	// view of what's on disk.
+	index := ingest.NewIndex()
+
+	if err := convertDocx(index); err != nil {
 		log.Fatalf("Errore durante la conversione: %v", err)
 	}
 
-	if err := run(); err != nil {
+	if err := syncGitSources(index); err != nil {
+		log.Fatalf("Errore durante la sincronizzazione dei repository git: %v", err)
+	}
+
+	watcher, ingestDir, err := startIngestWatcher(index)
+	if err != nil {
+		log.Fatalf("Errore durante l'avvio del watcher di ingest: %v", err)
+	}
+
+	if err := run(watcher, ingestDir); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+// startIngestWatcher watches ./documents for new or changed .docx files and
+// indexes them asynchronously, so new documents are picked up without a
+// restart. Workers are sized by INGEST_WORKERS (default 2). Conversions
+// publish their chunk paths to index, the same as the boot-time batch pass.
+// It also returns the watched directory, so callers can confine
+// operator-triggered reindex requests to it.
+func startIngestWatcher(index *ingest.Index) (*ingest.Watcher, string, error) {
+	indexer, err := newDocxIndexer(index)
+	if err != nil {
+		return nil, "", err
+	}
+
+	workers := 2
+	if n, err := strconv.Atoi(os.Getenv("INGEST_WORKERS")); err == nil && n > 0 {
+		workers = n
+	}
+
+	watcher := ingest.NewWatcher([]string{indexer.inputDir}, workers, func(path string) error {
+		if filepath.Ext(path) != ".docx" {
+			return nil
+		}
+		return indexer.convertAndMove(path)
+	})
+
+	go func() {
+		if err := watcher.Run(nil); err != nil {
+			log.Printf("Errore nel watcher di ingest: %v", err)
+		}
+	}()
+
+	return watcher, indexer.inputDir, nil
+}
+
+// conversionCacheDir holds the content-addressed conversion cache.
+// Deliberately outside ./data: cache blobs are the converter's plaintext
+// Markdown output, keyed by input hash rather than docID, and ./data is
+// where ACL-encrypted chunks live -- caching plaintext there would leak
+// every encrypted document's contents in the clear right next to it.
+const conversionCacheDir = "./.cache"
+
+// pruneCache evicts the least-recently-used conversion cache entries until
+// the cache's total size is at or below CACHE_MAX_BYTES.
+func pruneCache() error {
+	maxBytes, err := strconv.ParseInt(os.Getenv("CACHE_MAX_BYTES"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("CACHE_MAX_BYTES environment variable required for --prune: %w", err)
+	}
+	store, err := cache.NewStore(conversionCacheDir)
+	if err != nil {
+		return err
+	}
+	return store.Prune(maxBytes)
+}
+
+func run(watcher *ingest.Watcher, ingestDir string) error {
 	pubKey, err := fetchPublicKey()
 	if err != nil {
 		return fmt.Errorf("failed to fetch public key: %w", err)
@@ -59,11 +146,109 @@ func run() error {
 	agentService := agent.NewService(pubKey)
 
 	http.HandleFunc("/agent", agentService.ChatCompletion)
+	http.HandleFunc("/admin/cache", requireAdminToken(handleAdminCache))
+	http.HandleFunc("/admin/ingest/status", requireAdminToken(handleAdminIngestStatus(watcher)))
+	http.HandleFunc("/admin/ingest/reindex", requireAdminToken(handleAdminIngestReindex(watcher, ingestDir)))
 
 	fmt.Println("Listening on port", config.Port)
 	return http.ListenAndServe(":"+config.Port, nil)
 }
 
+// adminTokenEnv names the shared secret /admin/* handlers require, as
+// "Authorization: Bearer <token>". It is separate from ROOT_SECRET so
+// rotating operator access doesn't also rotate every document's session
+// key derivation.
+const adminTokenEnv = "ADMIN_TOKEN"
+
+// requireAdminToken gates an admin handler behind ADMIN_TOKEN. Admin
+// endpoints expose cache contents and can force arbitrary re-indexing, so
+// an unset ADMIN_TOKEN disables them (503) rather than leaving them open.
+func requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := os.Getenv(adminTokenEnv)
+		if token == "" {
+			http.Error(w, "admin endpoints disabled: ADMIN_TOKEN not configured", http.StatusServiceUnavailable)
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleAdminCache reports the conversion cache's entries, verbose enough
+// for an operator to decide what to prune.
+func handleAdminCache(w http.ResponseWriter, r *http.Request) {
+	store, err := cache.NewStore(conversionCacheDir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries, err := store.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// handleAdminIngestStatus reports the watcher's last known state for every
+// file it has seen, for an operator to confirm a drop landed or diagnose why
+// it didn't.
+func handleAdminIngestStatus(watcher *ingest.Watcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(watcher.Status())
+	}
+}
+
+// handleAdminIngestReindex forces the file at ?path= through the ingest
+// pipeline immediately, bypassing the filesystem-event debounce. path is
+// confined to ingestDir: convertAndMove both reads and then renames
+// whatever path it's given, so without this check the endpoint would let
+// any caller read and relocate an arbitrary file the process has access to.
+func handleAdminIngestReindex(watcher *ingest.Watcher, ingestDir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Query().Get("path")
+		if path == "" {
+			http.Error(w, "path query parameter required", http.StatusBadRequest)
+			return
+		}
+		confined, err := confineToDir(ingestDir, path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		watcher.Reindex(confined)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// confineToDir resolves path (relative paths are taken as relative to dir)
+// and verifies the result is actually inside dir, rejecting "../" escapes
+// and absolute paths elsewhere on disk.
+func confineToDir(dir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if absPath != absDir && !strings.HasPrefix(absPath, absDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be inside %s", dir)
+	}
+	return absPath, nil
+}
+
 // fetchPublicKey fetches the keys used to sign messages from copilot.  Checking
 // the signature with one of these keys verifies that the request to the
 // completions API comes from GitHub and not elsewhere on the internet.
@@ -141,123 +326,446 @@ func fetchPublicKey() (*ecdsa.PublicKey, error) {
 	return ecdsaKey, nil
 }
 
-// convertDocx legge i file .docx, estrae il contenuto testuale e lo salva in .md
-func convertDocx() error {
+// aclEncryptionEnabledEnv opts a deployment into per-document ACL
+// encryption. acl wraps each document's session key per principal, but
+// nothing in this checkout decrypts at request time yet -- agent.Service,
+// once it exists here, is where that consumer belongs (ragctl read is an
+// offline stand-in; see its doc comment). Encrypting by default with no
+// retrieval path to decrypt would silently make every ingested document
+// unreadable, so chunks are written in the clear unless an operator
+// explicitly opts in, e.g. to exercise ragctl read ahead of that consumer
+// landing.
+const aclEncryptionEnabledEnv = "ACL_ENCRYPT_CHUNKS"
+
+func aclEncryptionEnabled() bool {
+	return os.Getenv(aclEncryptionEnabledEnv) != ""
+}
+
+// convertDocx legge i file .docx, estrae il contenuto testuale e lo salva in .md,
+// cifrato con la chiave di sessione del documento se ACL_ENCRYPT_CHUNKS è
+// impostata (vedi acl.DeriveSessionKey e aclEncryptionEnabled).
+// This batch pass runs once at startup; the ingest watcher (see ingest.go)
+// covers files that show up afterwards without needing a restart.
+func convertDocx(index *ingest.Index) error {
+	indexer, err := newDocxIndexer(index)
+	if err != nil {
+		return err
+	}
+
+	// Leggi i file nella cartella input
+	files, err := os.ReadDir(indexer.inputDir)
+	if err != nil {
+		return fmt.Errorf("impossibile leggere la cartella %s: %w", indexer.inputDir, err)
+	}
+
+	for _, file := range files {
+		// Processa solo file con estensione .docx
+		if filepath.Ext(file.Name()) != ".docx" {
+			continue
+		}
+		inputFilePath := filepath.Join(indexer.inputDir, file.Name())
+		fmt.Printf("Elaborazione del file: %s\n", inputFilePath)
+		if err := indexer.convertAndMove(inputFilePath); err != nil {
+			log.Printf("Errore nell'elaborazione del file %s: %v", inputFilePath, err)
+		}
+	}
+
+	return nil
+}
+
+// docxIndexer bundles what both convertDocx and the ingest watcher need to
+// convert a single .docx file: its ACL store, conversion cache, the
+// directories it reads from and writes into, and the ingest.Index each
+// conversion publishes its chunk paths to.
+type docxIndexer struct {
+	inputDir     string
+	outputDir    string
+	processedDir string
+	rootSecret   string
+	aclStore     *acl.Store
+	cache        *cache.Store
+	index        *ingest.Index
+}
+
+func newDocxIndexer(index *ingest.Index) (*docxIndexer, error) {
 	inputDir := "./documents"
 	outputDir := "./data"
 	processedDir := filepath.Join(inputDir, "processed")
 
-	// Crea cartelle necessarie
+	rootSecret := os.Getenv("ROOT_SECRET")
+	if rootSecret == "" {
+		return nil, fmt.Errorf("ROOT_SECRET environment variable required")
+	}
+
 	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-		return fmt.Errorf("impossibile creare la cartella di output: %w", err)
+		return nil, fmt.Errorf("impossibile creare la cartella di output: %w", err)
 	}
 	if err := os.MkdirAll(processedDir, os.ModePerm); err != nil {
-		return fmt.Errorf("impossibile creare la cartella processed: %w", err)
+		return nil, fmt.Errorf("impossibile creare la cartella processed: %w", err)
 	}
 
-	// Leggi i file nella cartella input
-	files, err := os.ReadDir(inputDir)
+	conversionCache, err := cache.NewStore(conversionCacheDir)
 	if err != nil {
-		return fmt.Errorf("impossibile leggere la cartella %s: %w", inputDir, err)
+		return nil, err
 	}
 
-	for _, file := range files {
-		// Processa solo file con estensione .docx
-		if filepath.Ext(file.Name()) != ".docx" {
-			continue
+	return &docxIndexer{
+		inputDir:     inputDir,
+		outputDir:    outputDir,
+		processedDir: processedDir,
+		rootSecret:   rootSecret,
+		aclStore:     acl.NewStore(outputDir),
+		cache:        conversionCache,
+		index:        index,
+	}, nil
+}
+
+// convertAndMove converts a single .docx file, writes its chunks (encrypted
+// when aclEncryptionEnabled), and moves the source into processedDir. It is the unit of work both the
+// boot-time batch pass and the ingest watcher's per-event callback run.
+func (idx *docxIndexer) convertAndMove(inputFilePath string) error {
+	docID, err := idx.convert(inputFilePath)
+	if err != nil {
+		return err
+	}
+
+	processedFilePath := filepath.Join(idx.processedDir, filepath.Base(inputFilePath))
+	if err := os.Rename(inputFilePath, processedFilePath); err != nil {
+		return fmt.Errorf("impossibile spostare %s nella cartella processed: %w", inputFilePath, err)
+	}
+
+	fmt.Printf("File convertito e salvato: %s (%s)\n", docID, idx.outputDir)
+	return nil
+}
+
+// convert reads, converts, chunks, and (when aclEncryptionEnabled) encrypts
+// inputFilePath, returning its docID. Unlike convertAndMove it leaves the
+// source file in place; its only caller is convertAndMove, which both the
+// boot-time pass and the ingest watcher (reindex requests included) run.
+func (idx *docxIndexer) convert(inputFilePath string) (string, error) {
+	inputBytes, err := os.ReadFile(inputFilePath)
+	if err != nil {
+		return "", fmt.Errorf("impossibile leggere il file %s: %w", inputFilePath, err)
+	}
+
+	converter, ok := converters.For(".docx")
+	if !ok {
+		return "", fmt.Errorf("nessun converter registrato per .docx")
+	}
+
+	cacheKey := cache.Key(inputBytes, converter.Version(), "")
+	var markdown string
+	if cached, hit, err := idx.cache.Get(cacheKey); err != nil {
+		return "", fmt.Errorf("impossibile leggere la cache per %s: %w", inputFilePath, err)
+	} else if hit {
+		markdown = string(cached)
+	} else {
+		markdown, err = converter.Convert(inputFilePath, inputBytes)
+		if err != nil {
+			return "", err
 		}
+		if _, err := idx.cache.Put(cacheKey, inputFilePath+" (docx)", false, []byte(markdown)); err != nil {
+			return "", fmt.Errorf("impossibile scrivere la cache per %s: %w", inputFilePath, err)
+		}
+	}
 
-		inputFilePath := filepath.Join(inputDir, file.Name())
-		fmt.Printf("Elaborazione del file: %s\n", inputFilePath)
+	docID := strings.TrimSuffix(filepath.Base(inputFilePath), ".docx")
+
+	var sessionKey []byte
+	if aclEncryptionEnabled() {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("impossibile generare il salt per %s: %w", docID, err)
+		}
+		sessionKey = acl.DeriveSessionKey([]byte(idx.rootSecret), salt)
+
+		if err := grantConfiguredPrincipals(idx.aclStore, docID, salt, sessionKey); err != nil {
+			return "", fmt.Errorf("impossibile scrivere l'ACL per %s: %w", docID, err)
+		}
+	}
+
+	chunkPaths, err := writeChunks(idx.outputDir, docID, markdown, sessionKey)
+	if err != nil {
+		return "", fmt.Errorf("impossibile scrivere i chunk per %s: %w", docID, err)
+	}
+	idx.index.Publish(docID, chunkPaths)
+
+	return docID, nil
+}
+
+// writeChunks splits markdown on heading boundaries via the chunker
+// package, then writes each chunk's content and heading/offset sidecar to
+// dir as "{docID}-{index}.md" and "{docID}-{index}.json". It returns the
+// .md chunk paths written, for the caller to publish to an ingest.Index.
+// sessionKey is optional: when nil (the default while aclEncryptionEnabled
+// is off), chunks are written in the clear; when set, both files are sealed
+// under it with acl.EncryptChunk.
+func writeChunks(dir, docID, markdown string, sessionKey []byte) ([]string, error) {
+	var chunkPaths []string
+	for _, chunk := range chunker.Split(markdown, chunker.DefaultOptions) {
+		base := fmt.Sprintf("%s-%d", docID, chunk.Index)
+
+		content := []byte(chunk.Content)
+		if sessionKey != nil {
+			var err error
+			content, err = acl.EncryptChunk(sessionKey, content)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encrypt chunk %s: %w", base, err)
+			}
+		}
+		chunkPath := filepath.Join(dir, base+".md")
+		if err := os.WriteFile(chunkPath, content, 0644); err != nil {
+			return nil, fmt.Errorf("unable to write chunk %s: %w", base, err)
+		}
 
-		// Estrai il contenuto testuale dal file .docx
-		text, err := extractTextFromDocx(inputFilePath)
+		sidecar, err := json.Marshal(chunk)
 		if err != nil {
-			log.Printf("Errore nell'elaborazione del file %s: %v", inputFilePath, err)
-			continue
+			return nil, fmt.Errorf("unable to encode sidecar for %s: %w", base, err)
 		}
+		if sessionKey != nil {
+			sidecar, err = acl.EncryptChunk(sessionKey, sidecar)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encrypt sidecar for %s: %w", base, err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, base+".json"), sidecar, 0644); err != nil {
+			return nil, fmt.Errorf("unable to write sidecar %s: %w", base, err)
+		}
+
+		chunkPaths = append(chunkPaths, chunkPath)
+	}
+	return chunkPaths, nil
+}
+
+// grantConfiguredPrincipals wraps a freshly derived session key for every
+// principal listed in ACL_USERS (a comma-separated list of GitHub logins),
+// fetching each principal's public key from ACL_TRUST_ROOT.
+func grantConfiguredPrincipals(store *acl.Store, docID string, salt, sessionKey []byte) error {
+	users := os.Getenv("ACL_USERS")
+	if users == "" {
+		return nil
+	}
+	trustRoot := os.Getenv("ACL_TRUST_ROOT")
+	if trustRoot == "" {
+		return fmt.Errorf("ACL_TRUST_ROOT environment variable required when ACL_USERS is set")
+	}
 
-		// Scrivi il testo estratto in un file .md
-		outputFileName := strings.TrimSuffix(file.Name(), ".docx") + ".md"
-		outputFilePath := filepath.Join(outputDir, outputFileName)
-		err = os.WriteFile(outputFilePath, []byte(text), 0644)
+	for _, login := range strings.Split(users, ",") {
+		login = strings.TrimSpace(login)
+		if login == "" {
+			continue
+		}
+		pubKey, err := acl.FetchPrincipalPublicKey(trustRoot, login)
 		if err != nil {
-			log.Printf("Errore nella scrittura del file %s: %v", outputFilePath, err)
+			return fmt.Errorf("unable to fetch public key for %s: %w", login, err)
+		}
+		if err := store.Grant(docID, salt, sessionKey, login, pubKey); err != nil {
+			return fmt.Errorf("unable to grant %s access to %s: %w", login, docID, err)
+		}
+	}
+	return nil
+}
+
+// syncGitSources pulls the repositories listed in GIT_SOURCES (a
+// comma-separated list of "owner/repo@ref" or full clone URLs) and indexes
+// their documentation and code-comment-bearing files into ./data, the same
+// way convertDocx indexes local .docx files: split into chunks via
+// writeChunks and published to index, so ragctl and (once it exists) the
+// agent's retrieval path see git-sourced documents the same way they see
+// docx ones. Each call builds a fresh git.Source per entry, so every source
+// is fetched again on every call: the unchanged-remote-HEAD check in
+// Source.Sync only applies to repeat Syncs on the same Source, and the last
+// seen SHA is not kept across restarts. Documents that synced previously but are absent from the current
+// sync (deleted or renamed upstream) are archived into ./data/processed and
+// unpublished from index, the same way convertDocx retires a converted
+// .docx's source file.
+func syncGitSources(index *ingest.Index) error {
+	specs := os.Getenv("GIT_SOURCES")
+	if specs == "" {
+		return nil
+	}
+
+	rootSecret := os.Getenv("ROOT_SECRET")
+	if rootSecret == "" {
+		return fmt.Errorf("ROOT_SECRET environment variable required")
+	}
+
+	outputDir := "./data"
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("impossibile creare la cartella di output: %w", err)
+	}
+	aclStore := acl.NewStore(outputDir)
+
+	credStore := credentials.NewStore(config.ParseCredentialHelpers(os.Getenv("CREDENTIAL_HELPERS")))
+
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
 			continue
 		}
 
-		// Sposta il file originale nella cartella processed
-		processedFilePath := filepath.Join(processedDir, file.Name())
-		err = os.Rename(inputFilePath, processedFilePath)
+		source, err := git.NewSource(spec, credStore)
+		if err != nil {
+			return fmt.Errorf("invalid GIT_SOURCES entry %q: %w", spec, err)
+		}
+
+		docs, err := source.Sync(context.Background())
 		if err != nil {
-			log.Printf("Errore nello spostamento del file %s nella cartella processed: %v", inputFilePath, err)
+			return fmt.Errorf("unable to sync %s: %w", spec, err)
+		}
+		if docs == nil {
+			// Remote HEAD hasn't moved since the last sync: whatever is
+			// already on disk (and in the manifest) is still accurate.
 			continue
 		}
 
-		fmt.Printf("File convertito e salvato: %s\n", outputFilePath)
+		previousDocIDs, err := loadGitSourceManifest(outputDir, spec)
+		if err != nil {
+			log.Printf("Errore nella lettura del manifest per %s: %v", spec, err)
+		}
+
+		currentDocIDs := make(map[string]bool, len(docs))
+
+		for _, doc := range docs {
+			docID := gitDocID(spec, doc.Path)
+			currentDocIDs[docID] = true
+
+			var sessionKey []byte
+			if aclEncryptionEnabled() {
+				salt := make([]byte, 16)
+				if _, err := rand.Read(salt); err != nil {
+					log.Printf("Errore nella generazione del salt per %s: %v", docID, err)
+					continue
+				}
+				sessionKey = acl.DeriveSessionKey([]byte(rootSecret), salt)
+
+				if err := grantConfiguredPrincipals(aclStore, docID, salt, sessionKey); err != nil {
+					log.Printf("Errore nella scrittura dell'ACL per %s: %v", docID, err)
+					continue
+				}
+			}
+
+			chunkPaths, err := writeChunks(outputDir, docID, string(doc.Content), sessionKey)
+			if err != nil {
+				log.Printf("Errore nella scrittura dei chunk per %s: %v", docID, err)
+				continue
+			}
+			index.Publish(docID, chunkPaths)
+		}
+
+		for docID := range previousDocIDs {
+			if currentDocIDs[docID] {
+				continue
+			}
+			if err := archiveStaleGitDoc(outputDir, docID, index); err != nil {
+				log.Printf("Errore nell'archiviazione del documento obsoleto %s: %v", docID, err)
+			}
+		}
+
+		if err := saveGitSourceManifest(outputDir, spec, currentDocIDs); err != nil {
+			log.Printf("Errore nella scrittura del manifest per %s: %v", spec, err)
+		}
 	}
 
 	return nil
 }
 
-// extractTextFromDocx estrae il contenuto testuale da un file .docx
-func extractTextFromDocx(filePath string) (string, error) {
-	// Apri il file .docx come archivio ZIP
-	reader, err := zip.OpenReader(filePath)
+// gitSourceManifestPath is where syncGitSources remembers, across restarts,
+// which docIDs a given GIT_SOURCES entry produced as of its last sync --
+// syncGitSources has no periodic loop of its own, so this is the only record
+// of what "previously synced" means the next time the process starts.
+func gitSourceManifestPath(outputDir, spec string) string {
+	return filepath.Join(outputDir, ".git-sources", gitSpecToDash.Replace(spec)+".json")
+}
+
+// loadGitSourceManifest returns the set of docIDs spec produced as of its
+// last sync, or an empty set if there's no manifest yet.
+func loadGitSourceManifest(outputDir, spec string) (map[string]bool, error) {
+	raw, err := os.ReadFile(gitSourceManifestPath(outputDir, spec))
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("impossibile aprire il file .docx: %w", err)
+		return map[string]bool{}, err
+	}
+	var docIDs []string
+	if err := json.Unmarshal(raw, &docIDs); err != nil {
+		return map[string]bool{}, fmt.Errorf("unable to parse manifest for %s: %w", spec, err)
 	}
-	defer reader.Close()
+	set := make(map[string]bool, len(docIDs))
+	for _, docID := range docIDs {
+		set[docID] = true
+	}
+	return set, nil
+}
 
-	var documentXML *zip.File
-	for _, file := range reader.File {
-		if file.Name == "word/document.xml" {
-			documentXML = file
-			break
-		}
+// saveGitSourceManifest records docIDs as the set spec produced in the sync
+// that just completed.
+func saveGitSourceManifest(outputDir, spec string, docIDs map[string]bool) error {
+	path := gitSourceManifestPath(outputDir, spec)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	list := make([]string, 0, len(docIDs))
+	for docID := range docIDs {
+		list = append(list, docID)
+	}
+	sort.Strings(list)
+	raw, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(path, raw, 0644)
+}
 
-	if documentXML == nil {
-		return "", fmt.Errorf("document.xml non trovato nel file .docx")
+// archiveStaleGitDoc moves docID's chunk and sidecar files into
+// outputDir/processed and unpublishes it from index, the git-source
+// equivalent of convertAndMove moving a stale .docx source into processedDir
+// -- except here it's the *output* that's stale, since the upstream file was
+// deleted or renamed rather than converted again.
+func archiveStaleGitDoc(outputDir, docID string, index *ingest.Index) error {
+	processedDir := filepath.Join(outputDir, "processed")
+	if err := os.MkdirAll(processedDir, os.ModePerm); err != nil {
+		return err
 	}
 
-	// Leggi il contenuto di document.xml
-	rc, err := documentXML.Open()
+	matches, err := filepath.Glob(filepath.Join(outputDir, docID+"-*.md"))
 	if err != nil {
-		return "", fmt.Errorf("impossibile aprire document.xml: %w", err)
+		return err
 	}
-	defer rc.Close()
-
-	// Estrai il testo eliminando i tag XML
-	var buffer bytes.Buffer
-	_, err = io.Copy(&buffer, rc)
+	sidecars, err := filepath.Glob(filepath.Join(outputDir, docID+"-*.json"))
 	if err != nil {
-		return "", fmt.Errorf("impossibile leggere document.xml: %w", err)
+		return err
+	}
+	// The globs also match other documents whose docID starts with
+	// docID + "-"; keep only "{docID}-{index}.md" and "{docID}-{index}.json".
+	var owned []string
+	for _, path := range append(matches, sidecars...) {
+		rest := strings.TrimPrefix(filepath.Base(path), docID+"-")
+		rest = strings.TrimSuffix(strings.TrimSuffix(rest, ".md"), ".json")
+		if _, err := strconv.Atoi(rest); err == nil {
+			owned = append(owned, path)
+		}
 	}
 
-	// Rimuovi i tag XML
-	text := stripXMLTags(buffer.String())
-	return text, nil
+	for _, path := range owned {
+		dest := filepath.Join(processedDir, filepath.Base(path))
+		if err := os.Rename(path, dest); err != nil {
+			return fmt.Errorf("unable to archive %s: %w", path, err)
+		}
+	}
+
+	index.Unpublish(docID)
+	return nil
 }
 
-// stripXMLTags rimuove i tag XML da una stringa
-func stripXMLTags(input string) string {
-	var output strings.Builder
-	inTag := false
+// gitSpecToDash replaces path separators with "-" so a git source spec or a
+// path within it is safe to use as part of a ./data file name.
+var gitSpecToDash = strings.NewReplacer("/", "-", string(filepath.Separator), "-")
 
-	for _, char := range input {
-		if char == '<' {
-			inTag = true
-			continue
-		}
-		if char == '>' {
-			inTag = false
-			continue
-		}
-		if !inTag {
-			output.WriteRune(char)
-		}
-	}
+// gitDocID turns a git source spec and a file within it into a flat
+// document ID safe to use as a ./data file name.
+func gitDocID(spec, path string) string {
+	return gitSpecToDash.Replace(spec) + "-" + strings.TrimSuffix(gitSpecToDash.Replace(path), filepath.Ext(path))
+}
 
-	return strings.TrimSpace(output.String())
-}
\ No newline at end of file
@@ -0,0 +1,236 @@
+// Package git implements a sources.Source that pulls documentation and code
+// comments out of git repositories served over the smart-HTTP protocol.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/copilot-extensions/rag-extension/credentials"
+	"github.com/copilot-extensions/rag-extension/sources"
+)
+
+// indexed lists the extensions Sync considers document material. Anything
+// else in the tree is skipped.
+var indexed = map[string]bool{
+	".md":  true,
+	".rst": true,
+	".go":  true,
+	".py":  true,
+	".js":  true,
+	".ts":  true,
+}
+
+// Source fetches a single repository@ref over smart-HTTP (info/refs +
+// git-upload-pack, via the git binary) and exposes its documentation and
+// code-comment-bearing files as sources.Document.
+type Source struct {
+	// URL is the repository's clone URL, e.g. https://github.com/owner/repo.
+	URL string
+
+	// Ref is the branch or tag to fetch. Defaults to HEAD, the remote's
+	// default branch. A bare commit SHA is not accepted: the clone passes
+	// Ref to git clone --branch.
+	Ref string
+
+	// Credentials resolves the basic-auth secret sent on fetches, via the
+	// credentials package's docker-credential-helpers protocol. A Store
+	// with no configured helpers falls back to rag-credential-env
+	// (GITHUB_TOKEN), preserving prior behavior.
+	Credentials *credentials.Store
+
+	// lastSHA is the remote HEAD SHA observed by the previous Sync, used to
+	// skip re-fetching when nothing has moved.
+	lastSHA string
+}
+
+// NewSource parses a spec of the form "owner/repo@ref" or a full clone URL,
+// optionally suffixed with "@ref", into a Source. Ref defaults to HEAD.
+// creds resolves auth for the fetch; pass credentials.NewStore(nil) to rely
+// on the built-in GITHUB_TOKEN fallback.
+func NewSource(spec string, creds *credentials.Store) (*Source, error) {
+	url, ref, _ := strings.Cut(spec, "@")
+	if url == "" {
+		return nil, fmt.Errorf("empty git source spec")
+	}
+	if !strings.Contains(url, "://") {
+		url = "https://github.com/" + url
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return &Source{URL: url, Ref: ref, Credentials: creds}, nil
+}
+
+// Sync fetches the current state of Ref and returns its indexed documents.
+// If the remote HEAD SHA hasn't moved since the previous call, Sync returns
+// nil, nil without re-fetching. A fetched tree with no indexed files yields
+// an empty, non-nil slice.
+func (s *Source) Sync(ctx context.Context) ([]sources.Document, error) {
+	sha, err := s.remoteSHA(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %s@%s: %w", s.URL, s.Ref, err)
+	}
+	if sha == s.lastSHA {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "rag-git-source-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create clone directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := s.shallowClone(ctx, dir); err != nil {
+		return nil, err
+	}
+
+	ignore, err := loadRagignore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []sources.Document{}
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if !indexed[filepath.Ext(rel)] || ignore.matches(rel) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", rel, err)
+		}
+		docs = append(docs, sources.Document{Path: rel, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.lastSHA = sha
+	return docs, nil
+}
+
+// remoteSHA resolves Ref against the remote's info/refs advertisement
+// without fetching any objects, so unchanged sources cost a single
+// round-trip.
+func (s *Source) remoteSHA(ctx context.Context) (string, error) {
+	cmd := s.gitCommand(ctx, "ls-remote", s.URL, s.Ref, "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	var head string
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sha, ref := fields[0], fields[1]
+		if ref == s.Ref || ref == "refs/heads/"+s.Ref || ref == "refs/tags/"+s.Ref {
+			return sha, nil
+		}
+		if ref == "HEAD" {
+			head = sha
+		}
+	}
+	if head != "" {
+		return head, nil
+	}
+	return "", fmt.Errorf("ref %s not found", s.Ref)
+}
+
+// shallowClone performs a depth=1 clone of Ref into dir. Ref == "HEAD" omits
+// --branch: git clone --branch HEAD fails ("Remote branch HEAD not found in
+// upstream origin"), whereas a plain clone already checks out the remote's
+// default branch.
+func (s *Source) shallowClone(ctx context.Context, dir string) error {
+	args := []string{"clone", "--depth=1"}
+	if s.Ref != "HEAD" {
+		args = append(args, "--branch", s.Ref)
+	}
+	args = append(args, s.URL, dir)
+
+	cmd := s.gitCommand(ctx, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// gitCommand builds a git invocation with basic auth configured from
+// whatever credential s.Credentials resolves for s.URL. A failed credential
+// lookup falls back to an anonymous fetch, which is the right behavior for
+// public repositories but silently wrong for the private ones this feature
+// exists for -- so it's logged rather than swallowed.
+func (s *Source) gitCommand(ctx context.Context, args ...string) *exec.Cmd {
+	if cred, err := s.Credentials.Get(s.URL); err == nil && cred.Secret != "" {
+		username := cred.Username
+		if username == "" {
+			username = "x-access-token"
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + cred.Secret))
+		args = append([]string{"-c", "http.extraHeader=Authorization: Basic " + auth}, args...)
+	} else if err != nil {
+		log.Printf("git source %s: no credential (%v), fetching anonymously", s.URL, err)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+type ragignore struct {
+	patterns []string
+}
+
+func loadRagignore(dir string) (*ragignore, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ".ragignore"))
+	if os.IsNotExist(err) {
+		return &ragignore{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read .ragignore: %w", err)
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return &ragignore{patterns: patterns}, nil
+}
+
+func (r *ragignore) matches(path string) bool {
+	for _, pattern := range r.patterns {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+	}
+	return false
+}
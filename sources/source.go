@@ -0,0 +1,25 @@
+// Package sources defines the ingestion sources the extension can pull
+// documents from, alongside the local ./documents directory already handled
+// in main.
+package sources
+
+import "context"
+
+// Document is a single file pulled from a Source, ready to be handed to the
+// converter registry.
+type Document struct {
+	// Path identifies the document within its source (e.g. a repo-relative
+	// file path). It is used to derive the document ID downstream.
+	Path string
+
+	// Content is the raw file content.
+	Content []byte
+}
+
+// Source pulls documents from somewhere other than the local filesystem.
+type Source interface {
+	// Sync fetches the current state of the source and returns its
+	// documents. Implementations should make this cheap to call repeatedly;
+	// callers are expected to call it on every ingest cycle.
+	Sync(ctx context.Context) ([]Document, error)
+}